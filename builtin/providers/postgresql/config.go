@@ -0,0 +1,109 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/errwrap"
+	"github.com/lib/pq"
+)
+
+// Config holds the provider-level connection settings used to build Clients.
+type Config struct {
+	Host     string
+	Port     int
+	Database string
+	Username string
+	Password string
+	SSLMode  string
+
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+
+	SearchPath       string
+	StatementTimeout time.Duration
+	ApplicationName  string
+}
+
+// connStr builds the libpq connection string for this configuration.
+func (c *Config) connStr() string {
+	return fmt.Sprintf(
+		"host=%s port=%d dbname=%s user=%s password=%s sslmode=%s",
+		c.Host, c.Port, c.Database, c.Username, c.Password, c.SSLMode,
+	)
+}
+
+// NewClient opens the connection pool for this provider instance. A single
+// *sql.DB is shared by every resource so repeated CRUD calls reuse backend
+// connections instead of opening a fresh one per operation.
+func (c *Config) NewClient() (*Client, error) {
+	db, err := sql.Open("postgres", c.connStr())
+	if err != nil {
+		return nil, errwrap.Wrapf("Error opening PostgreSQL connection pool: {{err}}", err)
+	}
+
+	db.SetMaxOpenConns(c.MaxOpenConns)
+	db.SetMaxIdleConns(c.MaxIdleConns)
+	db.SetConnMaxLifetime(c.ConnMaxLifetime)
+
+	return &Client{
+		db:               db,
+		username:         c.Username,
+		searchPath:       c.SearchPath,
+		statementTimeout: c.StatementTimeout,
+		applicationName:  c.ApplicationName,
+	}, nil
+}
+
+// Client wraps the shared connection pool and the per-checkout session
+// settings applied to every connection handed out by Connect.
+type Client struct {
+	db       *sql.DB
+	username string
+
+	searchPath       string
+	statementTimeout time.Duration
+	applicationName  string
+}
+
+// Connect checks a single *sql.Conn out of the pool and applies this
+// provider's search_path/statement_timeout/application_name so long-running
+// applies are bounded and identifiable in pg_stat_activity. Callers must
+// Close the connection to return it to the pool.
+func (c *Client) Connect() (*sql.Conn, error) {
+	ctx := context.Background()
+
+	conn, err := c.db.Conn(ctx)
+	if err != nil {
+		return nil, errwrap.Wrapf("Error checking out PostgreSQL connection: {{err}}", err)
+	}
+
+	if c.searchPath != "" {
+		query := fmt.Sprintf("SET search_path = %s", pq.QuoteLiteral(c.searchPath))
+		if _, err := conn.ExecContext(ctx, query); err != nil {
+			conn.Close()
+			return nil, errwrap.Wrapf("Error setting search_path: {{err}}", err)
+		}
+	}
+
+	if c.statementTimeout > 0 {
+		query := fmt.Sprintf("SET statement_timeout = %d", c.statementTimeout/time.Millisecond)
+		if _, err := conn.ExecContext(ctx, query); err != nil {
+			conn.Close()
+			return nil, errwrap.Wrapf("Error setting statement_timeout: {{err}}", err)
+		}
+	}
+
+	if c.applicationName != "" {
+		query := fmt.Sprintf("SET application_name = %s", pq.QuoteLiteral(c.applicationName))
+		if _, err := conn.ExecContext(ctx, query); err != nil {
+			conn.Close()
+			return nil, errwrap.Wrapf("Error setting application_name: {{err}}", err)
+		}
+	}
+
+	return conn, nil
+}
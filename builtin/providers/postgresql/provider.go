@@ -0,0 +1,108 @@
+package postgresql
+
+import (
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// Provider returns a terraform.ResourceProvider for PostgreSQL.
+func Provider() terraform.ResourceProvider {
+	return &schema.Provider{
+		Schema: map[string]*schema.Schema{
+			"host": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"port": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  5432,
+			},
+			"database": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "postgres",
+			},
+			"username": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"password": {
+				Type:      schema.TypeString,
+				Optional:  true,
+				Sensitive: true,
+			},
+			"sslmode": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "prefer",
+			},
+			"max_connections": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     4,
+				Description: "Maximum number of open connections to the PostgreSQL server kept in the pool.",
+			},
+			"max_idle_connections": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     4,
+				Description: "Maximum number of idle connections kept in the pool.",
+			},
+			"max_connection_lifetime_seconds": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     0,
+				Description: "Maximum lifetime, in seconds, of a pooled connection. 0 means unlimited.",
+			},
+			"search_path": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "search_path applied to every connection checked out of the pool.",
+			},
+			"statement_timeout_seconds": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     0,
+				Description: "statement_timeout, in seconds, applied to every connection checked out of the pool. 0 means unlimited.",
+			},
+			"application_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "terraform",
+				Description: "application_name applied to every connection checked out of the pool.",
+			},
+		},
+
+		ResourcesMap: map[string]*schema.Resource{
+			"postgresql_database":  resourcePostgreSQLDatabase(),
+			"postgresql_schema":    resourcePostgreSQLSchema(),
+			"postgresql_extension": resourcePostgreSQLExtension(),
+		},
+
+		ConfigureFunc: providerConfigure,
+	}
+}
+
+func providerConfigure(d *schema.ResourceData) (interface{}, error) {
+	config := Config{
+		Host:     d.Get("host").(string),
+		Port:     d.Get("port").(int),
+		Database: d.Get("database").(string),
+		Username: d.Get("username").(string),
+		Password: d.Get("password").(string),
+		SSLMode:  d.Get("sslmode").(string),
+
+		MaxOpenConns:    d.Get("max_connections").(int),
+		MaxIdleConns:    d.Get("max_idle_connections").(int),
+		ConnMaxLifetime: time.Duration(d.Get("max_connection_lifetime_seconds").(int)) * time.Second,
+
+		SearchPath:       d.Get("search_path").(string),
+		StatementTimeout: time.Duration(d.Get("statement_timeout_seconds").(int)) * time.Second,
+		ApplicationName:  d.Get("application_name").(string),
+	}
+
+	return config.NewClient()
+}
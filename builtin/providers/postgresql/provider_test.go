@@ -0,0 +1,28 @@
+package postgresql
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+var testAccProviders map[string]terraform.ResourceProvider
+var testAccProvider *schema.Provider
+
+func init() {
+	testAccProvider = Provider().(*schema.Provider)
+	testAccProviders = map[string]terraform.ResourceProvider{
+		"postgresql": testAccProvider,
+	}
+}
+
+func testAccPreCheck(t *testing.T) {
+	if v := os.Getenv("PGHOST"); v == "" {
+		t.Fatal("PGHOST must be set for acceptance tests")
+	}
+	if v := os.Getenv("PGUSER"); v == "" {
+		t.Fatal("PGUSER must be set for acceptance tests")
+	}
+}
@@ -1,12 +1,14 @@
 package postgresql
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"strings"
 
 	"github.com/hashicorp/errwrap"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/internal/pgexec"
 	"github.com/lib/pq"
 )
 
@@ -28,6 +30,56 @@ func resourcePostgreSQLDatabase() *schema.Resource {
 				Optional: true,
 				Computed: true,
 			},
+			"template": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"encoding": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"lc_collate": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"lc_ctype": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"tablespace_name": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"connection_limit": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  -1,
+			},
+			"allow_connections": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"is_template": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"drop_on_destroy_even_if_template": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Flip is_template off before dropping the database if it was left marked as a template.",
+			},
 		},
 	}
 }
@@ -44,22 +96,69 @@ func resourcePostgreSQLDatabaseCreate(d *schema.ResourceData, meta interface{})
 	dbOwner := d.Get("owner").(string)
 	connUsername := client.username
 
-	var dbOwnerCfg string
-	if dbOwner != "" {
-		dbOwnerCfg = fmt.Sprintf("WITH OWNER=%s", pq.QuoteIdentifier(dbOwner))
-	} else {
-		dbOwnerCfg = ""
-	}
+	var stmts []pgexec.Statement
 
 	//needed in order to set the owner of the db if the connection user is not a superuser
-	err = grantRoleMembership(conn, dbOwner, connUsername)
+	grantStmt, err := grantRoleMembershipStatement(conn, dbOwner, connUsername)
 	if err != nil {
 		return err
 	}
+	if grantStmt != nil {
+		stmts = append(stmts, *grantStmt)
+	}
 
-	query := fmt.Sprintf("CREATE DATABASE %s %s", pq.QuoteIdentifier(dbName), dbOwnerCfg)
-	_, err = conn.Query(query)
-	if err != nil {
+	var createOpts []string
+	if v, ok := d.GetOk("template"); ok {
+		createOpts = append(createOpts, fmt.Sprintf("TEMPLATE=%s", pq.QuoteIdentifier(v.(string))))
+	}
+	if v, ok := d.GetOk("encoding"); ok {
+		createOpts = append(createOpts, fmt.Sprintf("ENCODING=%s", pq.QuoteLiteral(v.(string))))
+	}
+	if v, ok := d.GetOk("lc_collate"); ok {
+		createOpts = append(createOpts, fmt.Sprintf("LC_COLLATE=%s", pq.QuoteLiteral(v.(string))))
+	}
+	if v, ok := d.GetOk("lc_ctype"); ok {
+		createOpts = append(createOpts, fmt.Sprintf("LC_CTYPE=%s", pq.QuoteLiteral(v.(string))))
+	}
+	if v, ok := d.GetOk("tablespace_name"); ok {
+		createOpts = append(createOpts, fmt.Sprintf("TABLESPACE=%s", pq.QuoteIdentifier(v.(string))))
+	}
+	if v, ok := d.GetOkExists("connection_limit"); ok {
+		createOpts = append(createOpts, fmt.Sprintf("CONNECTION LIMIT=%d", v.(int)))
+	}
+	if v, ok := d.GetOkExists("allow_connections"); ok {
+		createOpts = append(createOpts, fmt.Sprintf("ALLOW_CONNECTIONS=%t", v.(bool)))
+	}
+	if v, ok := d.GetOkExists("is_template"); ok {
+		createOpts = append(createOpts, fmt.Sprintf("IS_TEMPLATE=%t", v.(bool)))
+	}
+
+	query := fmt.Sprintf("CREATE DATABASE %s", pq.QuoteIdentifier(dbName))
+	if len(createOpts) > 0 {
+		query += " WITH " + strings.Join(createOpts, " ")
+	}
+
+	dropStmt := pgexec.Statement{SQL: fmt.Sprintf("DROP DATABASE %s", pq.QuoteIdentifier(dbName))}
+
+	stmts = append(stmts, pgexec.Statement{
+		SQL: query,
+		Compensate: func(conn *sql.Conn) error {
+			_, err := conn.ExecContext(context.Background(), dropStmt.SQL)
+			return err
+		},
+	})
+
+	// CREATE DATABASE can't set the owner directly when the connecting
+	// role isn't a superuser, so it's applied as a follow-up ALTER. If
+	// that fails, undo the CREATE DATABASE rather than leaving an
+	// ownerless database behind.
+	if dbOwner != "" {
+		stmts = append(stmts, pgexec.Statement{
+			SQL: fmt.Sprintf("ALTER DATABASE %s OWNER TO %s", pq.QuoteIdentifier(dbName), pq.QuoteIdentifier(dbOwner)),
+		})
+	}
+
+	if err := pgexec.Exec(conn, stmts); err != nil {
 		return errwrap.Wrapf(fmt.Sprintf("Error creating database %s: {{err}}", dbName), err)
 	}
 
@@ -79,15 +178,33 @@ func resourcePostgreSQLDatabaseDelete(d *schema.ResourceData, meta interface{})
 	dbName := d.Get("name").(string)
 	connUsername := client.username
 	dbOwner := d.Get("owner").(string)
+
+	var stmts []pgexec.Statement
+
 	//needed in order to set the owner of the db if the connection user is not a superuser
-	err = grantRoleMembership(conn, dbOwner, connUsername)
+	grantStmt, err := grantRoleMembershipStatement(conn, dbOwner, connUsername)
 	if err != nil {
 		return err
 	}
+	if grantStmt != nil {
+		stmts = append(stmts, *grantStmt)
+	}
 
-	query := fmt.Sprintf("DROP DATABASE %s", pq.QuoteIdentifier(dbName))
-	_, err = conn.Query(query)
-	if err != nil {
+	if d.Get("is_template").(bool) && d.Get("drop_on_destroy_even_if_template").(bool) {
+		stmts = append(stmts, pgexec.Statement{
+			SQL: fmt.Sprintf("ALTER DATABASE %s IS_TEMPLATE false", pq.QuoteIdentifier(dbName)),
+			Compensate: func(conn *sql.Conn) error {
+				_, err := conn.ExecContext(context.Background(), fmt.Sprintf("ALTER DATABASE %s IS_TEMPLATE true", pq.QuoteIdentifier(dbName)))
+				return err
+			},
+		})
+	}
+
+	stmts = append(stmts, pgexec.Statement{
+		SQL: fmt.Sprintf("DROP DATABASE %s", pq.QuoteIdentifier(dbName)),
+	})
+
+	if err := pgexec.Exec(conn, stmts); err != nil {
 		return errwrap.Wrapf("Error dropping database: {{err}}", err)
 	}
 
@@ -106,18 +223,41 @@ func resourcePostgreSQLDatabaseRead(d *schema.ResourceData, meta interface{}) er
 
 	dbName := d.Get("name").(string)
 
-	var owner string
-	err = conn.QueryRow("SELECT pg_catalog.pg_get_userbyid(d.datdba) from pg_database d WHERE datname=$1", dbName).Scan(&owner)
+	var owner, template, encoding, lcCollate, lcCtype, tablespaceName string
+	var connLimit int
+	var allowConnections, isTemplate bool
+
+	err = conn.QueryRowContext(context.Background(), `SELECT pg_catalog.pg_get_userbyid(d.datdba),
+		pg_catalog.pg_encoding_to_char(d.encoding),
+		d.datcollate, d.datctype, d.datconnlimit, d.datallowconn, d.datistemplate,
+		t.spcname
+		FROM pg_catalog.pg_database d
+		JOIN pg_catalog.pg_tablespace t ON t.oid = d.dattablespace
+		WHERE d.datname = $1`, dbName).Scan(
+		&owner, &encoding, &lcCollate, &lcCtype, &connLimit, &allowConnections, &isTemplate, &tablespaceName)
 	switch {
 	case err == sql.ErrNoRows:
 		d.SetId("")
 		return nil
 	case err != nil:
 		return errwrap.Wrapf("Error reading database: {{err}}", err)
-	default:
-		d.Set("owner", owner)
-		return nil
 	}
+
+	// The template used to create a database is not retained by PostgreSQL,
+	// so avoid clobbering whatever the user configured.
+	template = d.Get("template").(string)
+
+	d.Set("owner", owner)
+	d.Set("template", template)
+	d.Set("encoding", encoding)
+	d.Set("lc_collate", lcCollate)
+	d.Set("lc_ctype", lcCtype)
+	d.Set("tablespace_name", tablespaceName)
+	d.Set("connection_limit", connLimit)
+	d.Set("allow_connections", allowConnections)
+	d.Set("is_template", isTemplate)
+
+	return nil
 }
 
 func resourcePostgreSQLDatabaseUpdate(d *schema.ResourceData, meta interface{}) error {
@@ -130,31 +270,130 @@ func resourcePostgreSQLDatabaseUpdate(d *schema.ResourceData, meta interface{})
 
 	dbName := d.Get("name").(string)
 
+	var stmts []pgexec.Statement
+
 	if d.HasChange("owner") {
-		owner := d.Get("owner").(string)
-		if owner != "" {
-			query := fmt.Sprintf("ALTER DATABASE %s OWNER TO %s", pq.QuoteIdentifier(dbName), pq.QuoteIdentifier(owner))
-			_, err := conn.Query(query)
-			if err != nil {
-				return errwrap.Wrapf("Error updating owner: {{err}}", err)
-			}
+		before, after := d.GetChange("owner")
+		oldOwner, newOwner := before.(string), after.(string)
+		if newOwner != "" {
+			stmts = append(stmts, pgexec.Statement{
+				SQL: fmt.Sprintf("ALTER DATABASE %s OWNER TO %s", pq.QuoteIdentifier(dbName), pq.QuoteIdentifier(newOwner)),
+				Compensate: func(conn *sql.Conn) error {
+					if oldOwner == "" {
+						return nil
+					}
+					_, err := conn.ExecContext(context.Background(), fmt.Sprintf("ALTER DATABASE %s OWNER TO %s", pq.QuoteIdentifier(dbName), pq.QuoteIdentifier(oldOwner)))
+					return err
+				},
+			})
+		}
+	}
+
+	if d.HasChange("tablespace_name") {
+		before, after := d.GetChange("tablespace_name")
+		oldTablespace, newTablespace := before.(string), after.(string)
+		if newTablespace != "" {
+			stmts = append(stmts, pgexec.Statement{
+				SQL: fmt.Sprintf("ALTER DATABASE %s SET TABLESPACE %s", pq.QuoteIdentifier(dbName), pq.QuoteIdentifier(newTablespace)),
+				Compensate: func(conn *sql.Conn) error {
+					if oldTablespace == "" {
+						return nil
+					}
+					_, err := conn.ExecContext(context.Background(), fmt.Sprintf("ALTER DATABASE %s SET TABLESPACE %s", pq.QuoteIdentifier(dbName), pq.QuoteIdentifier(oldTablespace)))
+					return err
+				},
+			})
+		}
+	}
+
+	if d.HasChange("connection_limit") {
+		before, after := d.GetChange("connection_limit")
+		oldLimit, newLimit := before.(int), after.(int)
+		stmts = append(stmts, pgexec.Statement{
+			SQL: fmt.Sprintf("ALTER DATABASE %s CONNECTION LIMIT %d", pq.QuoteIdentifier(dbName), newLimit),
+			Compensate: func(conn *sql.Conn) error {
+				_, err := conn.ExecContext(context.Background(), fmt.Sprintf("ALTER DATABASE %s CONNECTION LIMIT %d", pq.QuoteIdentifier(dbName), oldLimit))
+				return err
+			},
+		})
+	}
+
+	if d.HasChange("allow_connections") {
+		before, after := d.GetChange("allow_connections")
+		oldAllow, newAllow := before.(bool), after.(bool)
+		stmts = append(stmts, pgexec.Statement{
+			SQL: fmt.Sprintf("ALTER DATABASE %s ALLOW_CONNECTIONS %t", pq.QuoteIdentifier(dbName), newAllow),
+			Compensate: func(conn *sql.Conn) error {
+				_, err := conn.ExecContext(context.Background(), fmt.Sprintf("ALTER DATABASE %s ALLOW_CONNECTIONS %t", pq.QuoteIdentifier(dbName), oldAllow))
+				return err
+			},
+		})
+	}
+
+	if d.HasChange("is_template") {
+		before, after := d.GetChange("is_template")
+		oldTemplate, newTemplate := before.(bool), after.(bool)
+		stmts = append(stmts, pgexec.Statement{
+			SQL: fmt.Sprintf("ALTER DATABASE %s IS_TEMPLATE %t", pq.QuoteIdentifier(dbName), newTemplate),
+			Compensate: func(conn *sql.Conn) error {
+				_, err := conn.ExecContext(context.Background(), fmt.Sprintf("ALTER DATABASE %s IS_TEMPLATE %t", pq.QuoteIdentifier(dbName), oldTemplate))
+				return err
+			},
+		})
+	}
+
+	if len(stmts) > 0 {
+		if err := pgexec.Exec(conn, stmts); err != nil {
+			return errwrap.Wrapf("Error updating database: {{err}}", err)
 		}
 	}
 
 	return resourcePostgreSQLDatabaseRead(d, meta)
 }
 
-func grantRoleMembership(conn *sql.DB, dbOwner string, connUsername string) error {
-	if dbOwner != "" && dbOwner != connUsername {
-		query := fmt.Sprintf("GRANT %s TO %s", pq.QuoteIdentifier(dbOwner), pq.QuoteIdentifier(connUsername))
-		_, err := conn.Query(query)
-		if err != nil {
-			//is already member or role
-			if strings.Contains(err.Error(), "duplicate key value violates unique constraint") {
-				return nil
-			}
-			return errwrap.Wrapf("Error granting membership: {{err}}", err)
-		}
+// roleIsMember reports whether member already has group among its granted
+// roles, so callers can skip a redundant GRANT instead of relying on
+// PostgreSQL to reject (or silently accept) a duplicate.
+func roleIsMember(conn *sql.Conn, member, group string) (bool, error) {
+	var isMember bool
+	err := conn.QueryRowContext(context.Background(), `
+		SELECT EXISTS (
+			SELECT 1
+			FROM pg_catalog.pg_auth_members m
+			JOIN pg_catalog.pg_roles g ON g.oid = m.roleid
+			JOIN pg_catalog.pg_roles u ON u.oid = m.member
+			WHERE g.rolname = $1 AND u.rolname = $2
+		)
+	`, group, member).Scan(&isMember)
+	if err != nil {
+		return false, errwrap.Wrapf("Error checking role membership: {{err}}", err)
 	}
-	return nil
+	return isMember, nil
+}
+
+// grantRoleMembershipStatement builds the GRANT needed so connUsername can
+// set dbOwner as the owner of an object it doesn't itself own, paired with a
+// REVOKE Compensate so the membership doesn't outlive a failed batch. It
+// returns nil when no grant is needed (no owner, connUsername is the owner,
+// or connUsername is already a member of dbOwner).
+func grantRoleMembershipStatement(conn *sql.Conn, dbOwner, connUsername string) (*pgexec.Statement, error) {
+	if dbOwner == "" || dbOwner == connUsername {
+		return nil, nil
+	}
+
+	isMember, err := roleIsMember(conn, connUsername, dbOwner)
+	if err != nil {
+		return nil, err
+	}
+	if isMember {
+		return nil, nil
+	}
+
+	return &pgexec.Statement{
+		SQL: fmt.Sprintf("GRANT %s TO %s", pq.QuoteIdentifier(dbOwner), pq.QuoteIdentifier(connUsername)),
+		Compensate: func(conn *sql.Conn) error {
+			_, err := conn.ExecContext(context.Background(), fmt.Sprintf("REVOKE %s FROM %s", pq.QuoteIdentifier(dbOwner), pq.QuoteIdentifier(connUsername)))
+			return err
+		},
+	}, nil
 }
@@ -0,0 +1,247 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/hashicorp/errwrap"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/internal/pgexec"
+	"github.com/lib/pq"
+)
+
+func resourcePostgreSQLExtension() *schema.Resource {
+	return &schema.Resource{
+		Create: resourcePostgreSQLExtensionCreate,
+		Read:   resourcePostgreSQLExtensionRead,
+		Update: resourcePostgreSQLExtensionUpdate,
+		Delete: resourcePostgreSQLExtensionDelete,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"schema": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"version": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"cascade": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+		},
+	}
+}
+
+func resourcePostgreSQLExtensionCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	conn, err := client.Connect()
+	if err != nil {
+		return errwrap.Wrapf("Error connecting to PostgreSQL: {{err}}", err)
+	}
+	defer conn.Close()
+
+	extName := d.Get("name").(string)
+
+	var createOpts []string
+	if v, ok := d.GetOk("schema"); ok {
+		createOpts = append(createOpts, fmt.Sprintf("SCHEMA %s", pq.QuoteIdentifier(v.(string))))
+	}
+	if v, ok := d.GetOk("version"); ok {
+		createOpts = append(createOpts, fmt.Sprintf("VERSION %s", pq.QuoteLiteral(v.(string))))
+	}
+	if d.Get("cascade").(bool) {
+		createOpts = append(createOpts, "CASCADE")
+	}
+
+	query := fmt.Sprintf("CREATE EXTENSION %s", pq.QuoteIdentifier(extName))
+	for _, opt := range createOpts {
+		query += " " + opt
+	}
+
+	stmts := []pgexec.Statement{
+		{
+			SQL: query,
+			Compensate: func(conn *sql.Conn) error {
+				_, err := conn.ExecContext(context.Background(), fmt.Sprintf("DROP EXTENSION %s", pq.QuoteIdentifier(extName)))
+				return err
+			},
+		},
+	}
+
+	if err := pgexec.Exec(conn, stmts); err != nil {
+		return errwrap.Wrapf(fmt.Sprintf("Error creating extension %s: {{err}}", extName), err)
+	}
+
+	d.SetId(extName)
+
+	return resourcePostgreSQLExtensionRead(d, meta)
+}
+
+func resourcePostgreSQLExtensionDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	conn, err := client.Connect()
+	if err != nil {
+		return errwrap.Wrapf("Error connecting to PostgreSQL: {{err}}", err)
+	}
+	defer conn.Close()
+
+	extName := d.Get("name").(string)
+
+	query := fmt.Sprintf("DROP EXTENSION %s", pq.QuoteIdentifier(extName))
+	if d.Get("cascade").(bool) {
+		query += " CASCADE"
+	}
+
+	if _, err := conn.ExecContext(context.Background(), query); err != nil {
+		return errwrap.Wrapf("Error dropping extension: {{err}}", err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+func resourcePostgreSQLExtensionRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	conn, err := client.Connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	extName := d.Get("name").(string)
+
+	var schemaName, version string
+	err = conn.QueryRowContext(context.Background(), `
+		SELECT n.nspname, e.extversion
+		FROM pg_catalog.pg_extension e
+		JOIN pg_catalog.pg_namespace n ON n.oid = e.extnamespace
+		WHERE e.extname = $1
+	`, extName).Scan(&schemaName, &version)
+	switch {
+	case err == sql.ErrNoRows:
+		d.SetId("")
+		return nil
+	case err != nil:
+		return errwrap.Wrapf("Error reading extension: {{err}}", err)
+	}
+
+	d.Set("schema", schemaName)
+	d.Set("version", version)
+
+	return nil
+}
+
+func resourcePostgreSQLExtensionUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	conn, err := client.Connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	extName := d.Get("name").(string)
+
+	var stmts []pgexec.Statement
+
+	if d.HasChange("version") {
+		before, after := d.GetChange("version")
+		oldVersion, newVersion := before.(string), after.(string)
+		if newVersion != "" {
+			// Apply-time only: validated here rather than in a CustomizeDiff,
+			// so a bad version still surfaces during apply, not plan.
+			if err := validateExtensionVersion(conn, extName, newVersion); err != nil {
+				return err
+			}
+			stmts = append(stmts, pgexec.Statement{
+				SQL: fmt.Sprintf("ALTER EXTENSION %s UPDATE TO %s", pq.QuoteIdentifier(extName), pq.QuoteLiteral(newVersion)),
+				Compensate: func(conn *sql.Conn) error {
+					if oldVersion == "" {
+						return nil
+					}
+					_, err := conn.ExecContext(context.Background(), fmt.Sprintf("ALTER EXTENSION %s UPDATE TO %s", pq.QuoteIdentifier(extName), pq.QuoteLiteral(oldVersion)))
+					return err
+				},
+			})
+		}
+	}
+
+	if d.HasChange("schema") {
+		before, after := d.GetChange("schema")
+		oldSchema, newSchema := before.(string), after.(string)
+		if newSchema != "" {
+			stmts = append(stmts, pgexec.Statement{
+				SQL: fmt.Sprintf("ALTER EXTENSION %s SET SCHEMA %s", pq.QuoteIdentifier(extName), pq.QuoteIdentifier(newSchema)),
+				Compensate: func(conn *sql.Conn) error {
+					if oldSchema == "" {
+						return nil
+					}
+					_, err := conn.ExecContext(context.Background(), fmt.Sprintf("ALTER EXTENSION %s SET SCHEMA %s", pq.QuoteIdentifier(extName), pq.QuoteIdentifier(oldSchema)))
+					return err
+				},
+			})
+		}
+	}
+
+	if len(stmts) > 0 {
+		if err := pgexec.Exec(conn, stmts); err != nil {
+			return errwrap.Wrapf("Error updating extension: {{err}}", err)
+		}
+	}
+
+	return resourcePostgreSQLExtensionRead(d, meta)
+}
+
+// validateExtensionVersion checks the requested version against
+// pg_available_extension_versions so a bad upgrade target fails with the
+// list of available versions instead of a raw SQL error. It only runs from
+// Update, i.e. at apply time - there's no CustomizeDiff here, so a bad
+// version isn't caught until apply, just with a clearer message.
+func validateExtensionVersion(conn *sql.Conn, extName, version string) error {
+	ctx := context.Background()
+
+	var exists bool
+	err := conn.QueryRowContext(
+		ctx,
+		"SELECT EXISTS (SELECT 1 FROM pg_catalog.pg_available_extension_versions WHERE name = $1 AND version = $2)",
+		extName, version,
+	).Scan(&exists)
+	if err != nil {
+		return errwrap.Wrapf("Error validating extension version: {{err}}", err)
+	}
+	if exists {
+		return nil
+	}
+
+	rows, err := conn.QueryContext(
+		ctx,
+		"SELECT version FROM pg_catalog.pg_available_extension_versions WHERE name = $1 ORDER BY version",
+		extName,
+	)
+	if err != nil {
+		return errwrap.Wrapf("Error listing available extension versions: {{err}}", err)
+	}
+	defer rows.Close()
+
+	var available []string
+	for rows.Next() {
+		var v string
+		if err := rows.Scan(&v); err != nil {
+			return errwrap.Wrapf("Error reading available extension version: {{err}}", err)
+		}
+		available = append(available, v)
+	}
+
+	return fmt.Errorf("version %q is not available for extension %q; available versions: %v", version, extName, available)
+}
@@ -0,0 +1,145 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// TestAccPostgresqlSchema_Policy exercises a real grant -> Read round trip:
+// it applies a schema with a policy block, then reads the ACL straight back
+// out of pg_namespace via readSchemaPolicies (the same path Read uses) to
+// make sure the grants it just issued are actually visible afterward.
+func TestAccPostgresqlSchema_Policy(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlSchemaDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPostgresqlSchemaPolicyConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlSchemaExists("postgresql_schema.test", "tf_test_schema"),
+					testAccCheckPostgresqlSchemaPolicy("tf_test_schema", schemaPolicy{
+						role:   "PUBLIC",
+						create: true,
+						usage:  true,
+					}),
+					testAccCheckPostgresqlSchemaPolicyCount("tf_test_schema", 1),
+				),
+			},
+		},
+	})
+}
+
+const testAccPostgresqlSchemaPolicyConfig = `
+resource "postgresql_schema" "test" {
+  name = "tf_test_schema"
+
+  policy {
+    role   = "PUBLIC"
+    create = true
+    usage  = true
+  }
+}
+`
+
+func testAccCheckPostgresqlSchemaExists(resourceName, schemaName string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", resourceName)
+		}
+		if rs.Primary.ID != schemaName {
+			return fmt.Errorf("unexpected schema id: got %s, want %s", rs.Primary.ID, schemaName)
+		}
+		return nil
+	}
+}
+
+func testAccCheckPostgresqlSchemaPolicy(schemaName string, want schemaPolicy) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*Client)
+		conn, err := client.Connect()
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		policies, err := readSchemaPolicies(conn, schemaName)
+		if err != nil {
+			return err
+		}
+
+		for _, p := range policies {
+			if p["role"] != want.role {
+				continue
+			}
+			if p["create"] != want.create || p["usage"] != want.usage {
+				return fmt.Errorf("policy for %s on %s does not match: got %+v", want.role, schemaName, p)
+			}
+			return nil
+		}
+
+		return fmt.Errorf("no policy found for role %s on schema %s, got %+v", want.role, schemaName, policies)
+	}
+}
+
+// testAccCheckPostgresqlSchemaPolicyCount guards against the owner's own
+// ACL_ALL_RIGHTS_NAMESPACE grant leaking into the policy list as a phantom
+// entry: readSchemaPolicies must report only what a policy block actually
+// granted to others, not the owner's implicit default privileges.
+func testAccCheckPostgresqlSchemaPolicyCount(schemaName string, want int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*Client)
+		conn, err := client.Connect()
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		policies, err := readSchemaPolicies(conn, schemaName)
+		if err != nil {
+			return err
+		}
+
+		if len(policies) != want {
+			return fmt.Errorf("unexpected policy count for schema %s: got %d, want %d: %+v", schemaName, len(policies), want, policies)
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckPostgresqlSchemaDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Client)
+	conn, err := client.Connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "postgresql_schema" {
+			continue
+		}
+
+		var exists bool
+		err := conn.QueryRowContext(
+			context.Background(),
+			"SELECT EXISTS (SELECT 1 FROM pg_catalog.pg_namespace WHERE nspname = $1)",
+			rs.Primary.ID,
+		).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return fmt.Errorf("schema %s still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
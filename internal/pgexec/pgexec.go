@@ -0,0 +1,108 @@
+// Package pgexec runs batches of SQL statements against PostgreSQL while
+// keeping partial failures from leaking half-created objects into the
+// target cluster.
+package pgexec
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// Statement is a single SQL statement to execute as part of a batch. If a
+// later statement in the same batch fails while the batch runs without a
+// transaction (see Exec), Compensate (when set) is run to undo the effect of
+// this one; a transactional batch is rolled back instead and Compensate is
+// not called.
+type Statement struct {
+	SQL        string
+	Args       []interface{}
+	Compensate func(conn *sql.Conn) error
+}
+
+// nonTransactionalPrefixes lists statements that cannot run inside a PostgreSQL transaction
+// block at all, so a batch containing one forces the non-transactional
+// fallback in Exec.
+var nonTransactionalPrefixes = []string{
+	"CREATE DATABASE",
+	"ALTER DATABASE",
+	"DROP DATABASE",
+}
+
+func isNonTransactional(sql string) bool {
+	upper := strings.ToUpper(strings.TrimSpace(sql))
+	for _, prefix := range nonTransactionalPrefixes {
+		if strings.HasPrefix(upper, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Exec runs stmts in order over conn, a single checked-out connection. When
+// every statement can run inside a transaction, they're wrapped in one with
+// a SAVEPOINT between each so a later failure rolls back everything that
+// came before it. Statements like CREATE/ALTER/DROP DATABASE can't run in a
+// transaction block, so a batch containing one of those falls back to
+// sequential autocommit execution, unwinding already-applied statements via
+// their Compensate closures (in reverse order) if a later statement fails.
+func Exec(conn *sql.Conn, stmts []Statement) error {
+	for _, stmt := range stmts {
+		if isNonTransactional(stmt.SQL) {
+			return execCompensating(conn, stmts)
+		}
+	}
+	return execInTx(conn, stmts)
+}
+
+func execInTx(conn *sql.Conn, stmts []Statement) error {
+	ctx := context.Background()
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %s", err)
+	}
+
+	for i, stmt := range stmts {
+		savepoint := fmt.Sprintf("pgexec_%d", i)
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("SAVEPOINT %s", savepoint)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("error creating savepoint %s: %s", savepoint, err)
+		}
+		if _, err := tx.ExecContext(ctx, stmt.SQL, stmt.Args...); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("error executing %q: %s", stmt.SQL, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func execCompensating(conn *sql.Conn, stmts []Statement) error {
+	ctx := context.Background()
+
+	var completed []Statement
+
+	for _, stmt := range stmts {
+		if _, err := conn.ExecContext(ctx, stmt.SQL, stmt.Args...); err != nil {
+			compensate(conn, completed)
+			return fmt.Errorf("error executing %q: %s", stmt.SQL, err)
+		}
+		completed = append(completed, stmt)
+	}
+
+	return nil
+}
+
+// compensate runs the Compensate closure of each statement in reverse
+// order. Compensation errors are swallowed in favor of the original
+// failure, which is the one the caller needs to see.
+func compensate(conn *sql.Conn, completed []Statement) {
+	for i := len(completed) - 1; i >= 0; i-- {
+		if completed[i].Compensate == nil {
+			continue
+		}
+		completed[i].Compensate(conn)
+	}
+}